@@ -7,31 +7,48 @@ import (
     "github.com/gin-gonic/gin"
 )
 
-// StartBrowser starts a disposable browser container and returns URL
+// StartBrowser starts a disposable browser container and returns the session
 func StartBrowserHandler(c *gin.Context){
 	var req models.ContainerStartRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error":"Invalid request"})
-		
-		return 
+
+		return
 	}
 
-	url, err := containers.StartBrowserContainer(req.SessionID)
+	manager, err := containers.Default()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sandbox manager unavailable"})
+		return
+	}
+
+	session, err := manager.StartBrowserContainer(c.Request.Context(), req.OwnerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start browser container"})
-		return 
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"access_url": url})
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": session.ID,
+		"access_url": session.AccessURL,
+		"expires_at": session.ExpiresAt,
+	})
 }
 
 // StopBrowser stops the browser container by session ID
 func StopBrowserHandler(c *gin.Context){
 	sessionId := c.Param("sessionId")
-	if err := containers.StopBrowserContainer(sessionId); err != nil {
+
+	manager, err := containers.Default()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sandbox manager unavailable"})
+		return
+	}
+
+	if err := manager.StopBrowserContainer(c.Request.Context(), sessionId); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error":"Failed to stop browser container"})
-		return 
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Browser container stopped"})
-}
\ No newline at end of file
+}
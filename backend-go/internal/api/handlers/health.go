@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/mlclient"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthCheck reports service liveness together with which ML backend is
+// currently serving phishing checks, so operators can see a circuit
+// breaker fallback to the local ONNX model as it happens.
+func HealthCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":     "ok",
+		"ml_backend": mlclient.ServingBackend(),
+	})
+}
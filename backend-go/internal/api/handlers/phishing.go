@@ -1,30 +1,139 @@
 package handlers
 
 import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"backend/internal/features"
 	"backend/internal/mlclient"
 	"backend/internal/models"
-	"net/http"
+	"backend/internal/observability"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
-// PhishingCheck receives text input. calls ML service, and returns result
+// allowedStreamOrigins is the CORS_ALLOWED_ORIGINS allowlist (comma-
+// separated) for the streaming WebSocket endpoint. Left empty, no
+// cross-origin WebSocket upgrade is allowed - same-origin callers are
+// still fine, since their Origin host matches the request's Host.
+var allowedStreamOrigins = parseAllowedOrigins(os.Getenv("CORS_ALLOWED_ORIGINS"))
+
+func parseAllowedOrigins(raw string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, origin := range strings.Split(raw, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" {
+			allowed[origin] = true
+		}
+	}
+	return allowed
+}
+
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// A bare "true" here lets any third-party page open a cross-site
+	// WebSocket to this endpoint in a victim's browser (cross-site
+	// WebSocket hijacking). Only requests with no Origin header, same-
+	// origin requests, or an explicitly configured origin are allowed.
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		return allowedStreamOrigins[origin]
+	},
+}
+
+// featureExtractor caches per-URL WHOIS/TLS lookups for 30 minutes across
+// requests.
+var featureExtractor = features.NewExtractor(30 * time.Minute)
+
+// PhishingCheck receives text input, extracts URL/HTML features when
+// present, calls the ML service, and returns the combined result.
 func PhishingCheck(c *gin.Context) {
+	start := time.Now()
+	ctx, span := observability.StartSpan(c.Request.Context(), "handler.PhishingCheck")
+	defer span.End()
+
 	var req models.PhishingCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request payload",
 		})
-		return 
+		return
+	}
+
+	observability.PhishingCheckRequestSize.Observe(float64(len(req.Text)))
+
+	var extracted features.Result
+	if len(req.URLs) > 0 || req.HTML != "" {
+		extracted = featureExtractor.Extract(ctx, req.URLs, req.HTML)
 	}
 
-	result, err := mlclient.PredictPhishing(req.Text)
+	result, err := mlclient.PredictPhishing(ctx, req.Text, extracted.Vector)
+	observability.PhishingCheckDuration.
+		WithLabelValues(mlclient.ServingBackend()).
+		Observe(time.Since(start).Seconds())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError,gin.H{
+		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "ML service error",
 		})
-		return 
+		return
 	}
 
+	observability.PhishingScore.Observe(result.PhishingProbability)
+	observability.PhishingPredictions.WithLabelValues(result.Prediction).Inc()
+
+	result.Features = extracted.Vector
+	result.URLVerdicts = extracted.Verdicts
+
+	observability.Audit(observability.AuditEntry{
+		User:       req.UserID,
+		SessionID:  req.SessionID,
+		URLChecked: firstURL(req.URLs),
+		Verdict:    result.Prediction,
+	})
+
 	c.JSON(http.StatusOK, result)
+}
+
+// firstURL returns the first URL submitted with a check, or "" if none
+// were, for the audit log's single url_checked field.
+func firstURL(urls []string) string {
+	if len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
+
+// PhishingCheckStream upgrades the request to a WebSocket and proxies it
+// to the ML inference service's streaming endpoint, so large emails, HTML
+// dumps, or file uploads can be classified incrementally instead of in a
+// single JSON round trip. Partial and final verdicts are pushed back to
+// the client as they become available.
+func PhishingCheckStream(c *gin.Context) {
+	conn, err := streamUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unable to upgrade connection",
+		})
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if err := mlclient.StreamPredictPhishing(ctx, conn); err != nil {
+		conn.WriteJSON(gin.H{"error": "ML service stream error"})
+	}
 }
\ No newline at end of file
@@ -10,7 +10,9 @@ func RegisterRoutes(router *gin.Engine) {
 	//health check endpoint
 	router.GET("/health", handlers.HealthCheck)
 	//other api endpoints can be registerer here
-	router.POST("/api/v1/phishing-check", handlers.PhishingCheck())
+	router.POST("/api/v1/phishing-check", handlers.PhishingCheck)
+	//streams large/incremental input to the ML service over a WebSocket
+	router.GET("/api/v1/phishing-check/stream", handlers.PhishingCheckStream)
 
 	//add browser/desktop container endpoint later
 	router.POST("/api/v1/browser/start", handlers.StartBrowserHandler)
@@ -2,80 +2,121 @@ package containers
 
 import (
 	"context"
-	"fmt"
+	"io"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 )
 
-// StartBrowserContainer starts a disposable chromium container and returns access url
-func StartBrowserContainer(sessionID string) (string, error){
-	cli, err := client.NewClientWithOpts(client.FromEnv)
-	if err != nil {
-		return "", err
-	}
+const (
+	browserImage      = "linuxserver/chromium:latest"
+	browserPortNumber = 3000
+	browserPort       = "3000/tcp"
+	traefikNetwork    = "traefik"
+)
 
-	ctx := context.Background()
-	//Define ports and container config
+// buildContainerConfig returns the container.Config for a disposable
+// chromium browser container, labelled so the janitor can find it again.
+func buildContainerConfig(labels map[string]string) *container.Config {
 	exposedPorts := nat.PortSet{
-		"3000/tcp": struct {}{},
-	}
-	portBindings := nat.PortMap {
-		"3000/tcp": []nat.PortBinding{
-			{
-				HostIP: "0.0.0.0",
-				HostPort: "",
-			},
-		},
+		browserPort: struct{}{},
 	}
 
-	//create container config
-	containerConfig := &container.Config{
-		Image: "linuxserver/chromium:latest",
+	return &container.Config{
+		Image:        browserImage,
 		ExposedPorts: exposedPorts,
+		Labels:       labels,
 		Env: []string{
 			"PUID=1000",
 			"PGID=1000",
 			"TZ=UTC",
 			"CHROME_CLI=https://www.duckduckgo.com",
-            "CHROME_OPTS=--no-sandbox --disable-dev-shm-usage",
+			"CHROME_OPTS=--no-sandbox --disable-dev-shm-usage",
+		},
+	}
+}
+
+// buildHostConfig returns a hardened HostConfig: read-only rootfs (with
+// tmpfs mounts for the paths linuxserver/chromium needs to write to), all
+// capabilities dropped bar the handful chromium needs, the default seccomp
+// profile, CPU/memory/PID quotas, and a random host port bound to the
+// container's browser port.
+func buildHostConfig() *container.HostConfig {
+	portBindings := nat.PortMap{
+		browserPort: []nat.PortBinding{
+			{
+				HostIP:   "0.0.0.0",
+				HostPort: "",
+			},
 		},
 	}
 
-	hostConfig := &container.HostConfig{
+	return &container.HostConfig{
 		PortBindings: portBindings,
-		AutoRemove: true,
-		ShmSize: 3221225472 , // 3GB
-		SecurityOpt: []string{"seccomp=unconfined"},
+		AutoRemove:   true,
+		ShmSize:      3221225472, // 3GB
+		// ReadonlyRootfs blocks the image's own writes to /config and
+		// /tmp, so both are mounted as tmpfs rather than left on the
+		// image's (removed-on-exit anyway) rootfs.
+		ReadonlyRootfs: true,
+		Tmpfs: map[string]string{
+			"/tmp":    "rw,size=512m",
+			"/config": "rw,size=512m",
+		},
+		CapDrop:     []string{"ALL"},
+		CapAdd:      []string{"CHOWN", "SETUID", "SETGID"},
+		NetworkMode: container.NetworkMode(traefikNetwork),
+		Resources: container.Resources{
+			NanoCPUs:  1_000_000_000, // 1 vCPU
+			Memory:    2 * 1024 * 1024 * 1024,
+			PidsLimit: int64Ptr(256),
+		},
 	}
+}
 
-	networkingConfig := &network.NetworkingConfig{
-		//create container
-		resp, err := cli.ContainerCreate(ctx, containerConfig,hostConfig,networkingConfig, nil , sessionID )
+// buildNetworkingConfig joins the container to the isolated, user-defined
+// traefik bridge network rather than the Docker default bridge.
+func buildNetworkingConfig(ctx context.Context, cli *client.Client) (*network.NetworkingConfig, error) {
+	return &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			traefikNetwork: {},
+		},
+	}, nil
+}
+
+// ensureImage pulls ref if the daemon doesn't already have it cached.
+func ensureImage(ctx context.Context, cli *client.Client, ref string) error {
+	_, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err == nil {
+		return nil
 	}
 
+	out, err := cli.ImagePull(ctx, ref, types.ImagePullOptions{})
 	if err != nil {
-		return "", err
+		return err
 	}
+	defer out.Close()
 
-	if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
-		return "", err
-	}
+	_, err = io.Copy(io.Discard, out)
+	return err
+}
+
+// listManagedContainers lists every container (running or not) labelled
+// managed-by=thesis, regardless of whether this process knows about it.
+func listManagedContainers(ctx context.Context, cli *client.Client) ([]types.Container, error) {
+	f := filters.NewArgs()
+	f.Add("label", managedByLabel+"="+managedByValue)
 
-	//Construct access URL (using traefik and domain logic)
-	accessURL := fmt.Sprintf("https://%s.disposable-services.duckdns.go", sessionID)
-	return accessURL, nil 
+	return cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: f,
+	})
 }
 
-// StopBrowserContainer stops container by session ID
-func StopBrowserContainer(sessionID string) error {
-    cli, err := client.NewClientWithOpts(client.FromEnv)
-    if err != nil {
-        return err
-    }
-    ctx := context.Background()
-    return cli.ContainerStop(ctx, sessionID, nil)
+func int64Ptr(v int64) *int64 {
+	return &v
 }
@@ -0,0 +1,454 @@
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/internal/observability"
+	"backend/internal/routing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// managedByLabel/managedByValue mark every container this package
+	// creates so the janitor can find them again after a backend restart.
+	managedByLabel = "managed-by"
+	managedByValue = "thesis"
+	sessionIDLabel = "thesis.session-id"
+	ownerIDLabel   = "thesis.owner-id"
+
+	defaultSessionTTL   = 15 * time.Minute
+	defaultJanitorEvery = time.Minute
+)
+
+// Session describes a running disposable browser container handed out to
+// a caller. ID is what callers reference going forward — it is
+// never assumed to equal ContainerID.
+type Session struct {
+	ID          string
+	ContainerID string
+	AccessURL   string
+	ExpiresAt   time.Time
+	OwnerID     string
+}
+
+func (s *Session) expired(now time.Time) bool {
+	return now.After(s.ExpiresAt)
+}
+
+// SandboxManager owns the lifecycle of every disposable browser container:
+// it enforces a per-session TTL and per-owner concurrency limit, and runs a
+// background janitor that sweeps away containers past their TTL or left
+// over from a previous crash. The in-memory session table is the source of
+// truth; Store, when set, mirrors it so sessions survive a backend restart.
+type SandboxManager struct {
+	cli *client.Client
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	byOwner  map[string]int
+
+	sessionTTL   time.Duration
+	maxPerOwner  int
+	janitorEvery time.Duration
+
+	// Store optionally mirrors session state outside process memory (e.g.
+	// Redis) so a restarted backend can rediscover sessions it owns before
+	// the janitor treats them as orphans. Nil means in-memory only.
+	Store SessionStore
+
+	// router makes each session's AccessURL actually routable. Nil means
+	// sessions get an AccessURL but nothing serves it, same as before
+	// this package had a routing.Router to hand it to.
+	router *routing.Router
+
+	stop chan struct{}
+}
+
+// SessionStore is the optional persistence backend for sessions (e.g. a
+// Redis-backed implementation). A SandboxManager works fine with a nil
+// Store; it then only tracks sessions in memory.
+type SessionStore interface {
+	Save(ctx context.Context, s *Session) error
+	Delete(ctx context.Context, sessionID string) error
+	List(ctx context.Context) ([]*Session, error)
+}
+
+// ManagerOption configures a SandboxManager at construction time.
+type ManagerOption func(*SandboxManager)
+
+// WithSessionTTL overrides the default per-session lifetime.
+func WithSessionTTL(ttl time.Duration) ManagerOption {
+	return func(m *SandboxManager) { m.sessionTTL = ttl }
+}
+
+// WithMaxSessionsPerOwner caps how many concurrent sessions one owner may
+// hold; zero means unlimited.
+func WithMaxSessionsPerOwner(max int) ManagerOption {
+	return func(m *SandboxManager) { m.maxPerOwner = max }
+}
+
+// WithStore attaches a SessionStore used to persist session state.
+func WithStore(store SessionStore) ManagerOption {
+	return func(m *SandboxManager) { m.Store = store }
+}
+
+// WithRouter attaches a routing.Router so StartBrowserContainer registers
+// a real Traefik route for each session and StopBrowserContainer tears it
+// down again.
+func WithRouter(router *routing.Router) ManagerOption {
+	return func(m *SandboxManager) { m.router = router }
+}
+
+// NewSandboxManager builds a manager backed by the local Docker daemon and
+// starts its background janitor goroutine. Callers should call Close when
+// shutting down to stop the janitor.
+func NewSandboxManager(opts ...ManagerOption) (*SandboxManager, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SandboxManager{
+		cli:          cli,
+		sessions:     make(map[string]*Session),
+		byOwner:      make(map[string]int),
+		sessionTTL:   defaultSessionTTL,
+		janitorEvery: defaultJanitorEvery,
+		stop:         make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// Reload sessions a previous run persisted, so the janitor doesn't
+	// reap their containers as orphans.
+	if m.Store != nil {
+		if saved, err := m.Store.List(context.Background()); err == nil {
+			for _, s := range saved {
+				m.sessions[s.ID] = s
+				m.byOwner[s.OwnerID]++
+			}
+		}
+	}
+
+	go m.runJanitor()
+
+	return m, nil
+}
+
+// Close stops the janitor goroutine. It does not stop any running
+// containers.
+func (m *SandboxManager) Close() {
+	close(m.stop)
+}
+
+var (
+	defaultManagerOnce sync.Once
+	defaultManager     *SandboxManager
+	defaultManagerErr  error
+)
+
+// defaultTraefikConfigDir is where the process-wide manager writes
+// per-session dynamic config, matching the file provider directory
+// configured in traefik.yml.
+const defaultTraefikConfigDir = "/etc/traefik/dynamic"
+
+// Default returns the process-wide SandboxManager, creating it on first
+// use with the package defaults (15 minute TTL, 2 sessions per owner,
+// Traefik routing via defaultTraefikConfigDir).
+func Default() (*SandboxManager, error) {
+	defaultManagerOnce.Do(func() {
+		defaultManager, defaultManagerErr = NewSandboxManager(
+			WithMaxSessionsPerOwner(2),
+			WithRouter(routing.NewRouter(defaultTraefikConfigDir)),
+		)
+	})
+	return defaultManager, defaultManagerErr
+}
+
+// StartBrowserContainer starts a disposable chromium container for ownerID
+// and returns the Session tracking it. It refuses to start a new session
+// once ownerID is already at its concurrent-session limit.
+func (m *SandboxManager) StartBrowserContainer(ctx context.Context, ownerID string) (*Session, error) {
+	if !m.reserveSlot(ownerID) {
+		return nil, fmt.Errorf("owner %s already has %d active sessions", ownerID, m.maxPerOwner)
+	}
+
+	session, err := m.startReservedContainer(ctx, ownerID)
+	if err != nil {
+		m.releaseSlot(ownerID)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// reserveSlot atomically checks and increments the caller's concurrent
+// session count, so two concurrent starts for the same owner can't both
+// pass the limit check before either registers its session (TOCTOU).
+// Call releaseSlot if the reservation doesn't end in a started session.
+func (m *SandboxManager) reserveSlot(ownerID string) bool {
+	if m.maxPerOwner <= 0 {
+		return true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.byOwner[ownerID] >= m.maxPerOwner {
+		return false
+	}
+	m.byOwner[ownerID]++
+	return true
+}
+
+func (m *SandboxManager) releaseSlot(ownerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.byOwner[ownerID] > 0 {
+		m.byOwner[ownerID]--
+	}
+}
+
+func (m *SandboxManager) startReservedContainer(ctx context.Context, ownerID string) (*Session, error) {
+	sessionID := uuid.NewString()
+
+	if err := ensureImage(ctx, m.cli, browserImage); err != nil {
+		return nil, fmt.Errorf("pulling %s: %w", browserImage, err)
+	}
+
+	labels := map[string]string{
+		managedByLabel: managedByValue,
+		sessionIDLabel: sessionID,
+		ownerIDLabel:   ownerID,
+	}
+
+	containerConfig := buildContainerConfig(labels)
+	hostConfig := buildHostConfig()
+	networkingConfig, err := buildNetworkingConfig(ctx, m.cli)
+	if err != nil {
+		return nil, err
+	}
+
+	// Named after the session so the routing package (and Traefik, over
+	// the shared network) can address it by a stable hostname rather than
+	// an IP that changes every time the container is recreated.
+	resp, err := m.cli.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, nil, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return nil, err
+	}
+
+	if m.router != nil {
+		if err := m.router.Register(sessionID, sessionID, browserPortNumber); err != nil {
+			_ = m.cli.ContainerStop(ctx, resp.ID, nil)
+			return nil, fmt.Errorf("registering route for session %s: %w", sessionID, err)
+		}
+	}
+
+	session := &Session{
+		ID:          sessionID,
+		ContainerID: resp.ID,
+		AccessURL:   routing.AccessURL(sessionID),
+		ExpiresAt:   time.Now().Add(m.sessionTTL),
+		OwnerID:     ownerID,
+	}
+
+	m.mu.Lock()
+	m.sessions[sessionID] = session
+	m.mu.Unlock()
+
+	if m.Store != nil {
+		if err := m.Store.Save(ctx, session); err != nil {
+			// Don't leave a container running, a route registered, and
+			// an owner slot consumed behind a request that got a 500
+			// with no session ID to ever stop.
+			m.mu.Lock()
+			delete(m.sessions, sessionID)
+			m.mu.Unlock()
+			if m.router != nil {
+				_ = m.router.Teardown(sessionID)
+			}
+			_ = m.cli.ContainerStop(ctx, resp.ID, nil)
+			return nil, fmt.Errorf("saving session %s: %w", sessionID, err)
+		}
+	}
+
+	observability.ContainerStarts.Inc()
+	observability.Log.Info("sandbox session started",
+		zap.String("session_id", sessionID),
+		zap.String("container_id", resp.ID),
+		zap.String("owner_id", ownerID))
+	observability.Audit(observability.AuditEntry{
+		User:        ownerID,
+		SessionID:   sessionID,
+		ContainerID: resp.ID,
+		Verdict:     "session_started",
+	})
+
+	return session, nil
+}
+
+// StopBrowserContainer stops and removes the container behind sessionID.
+func (m *SandboxManager) StopBrowserContainer(ctx context.Context, sessionID string) error {
+	m.mu.Lock()
+	session, ok := m.sessions[sessionID]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown session %q", sessionID)
+	}
+
+	if err := m.cli.ContainerStop(ctx, session.ContainerID, nil); err != nil {
+		return err
+	}
+
+	m.forget(ctx, session, "requested")
+	return nil
+}
+
+func (m *SandboxManager) forget(ctx context.Context, session *Session, reason string) {
+	m.mu.Lock()
+	delete(m.sessions, session.ID)
+	if m.byOwner[session.OwnerID] > 0 {
+		m.byOwner[session.OwnerID]--
+	}
+	m.mu.Unlock()
+
+	if m.router != nil {
+		_ = m.router.Teardown(session.ID)
+	}
+
+	if m.Store != nil {
+		_ = m.Store.Delete(ctx, session.ID)
+	}
+
+	observability.ContainerStops.WithLabelValues(reason).Inc()
+	observability.ContainerResourceUsage.DeletePartialMatch(map[string]string{"session_id": session.ID})
+	observability.Log.Info("sandbox session stopped",
+		zap.String("session_id", session.ID),
+		zap.String("container_id", session.ContainerID),
+		zap.String("reason", reason))
+	observability.Audit(observability.AuditEntry{
+		User:        session.OwnerID,
+		SessionID:   session.ID,
+		ContainerID: session.ContainerID,
+		Verdict:     "session_stopped:" + reason,
+	})
+}
+
+// runJanitor periodically force-stops and removes containers that are past
+// their session TTL, plus any container labelled managed-by=thesis that
+// this process has no record of — left behind by a previous crash.
+func (m *SandboxManager) runJanitor() {
+	ticker := time.NewTicker(m.janitorEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.sweep()
+		}
+	}
+}
+
+func (m *SandboxManager) sweep() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	m.mu.Lock()
+	expired := make([]*Session, 0)
+	now := time.Now()
+	for _, s := range m.sessions {
+		if s.expired(now) {
+			expired = append(expired, s)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, s := range expired {
+		if err := m.cli.ContainerStop(ctx, s.ContainerID, nil); err == nil {
+			m.forget(ctx, s, "ttl_expired")
+		}
+	}
+
+	m.sampleResourceUsage(ctx)
+	m.reapOrphans(ctx)
+}
+
+// sampleResourceUsage pulls a one-shot cli.ContainerStats sample for every
+// active session and publishes it as a gauge, so operators can see CPU
+// and memory pressure per session without shelling into the host.
+func (m *SandboxManager) sampleResourceUsage(ctx context.Context) {
+	m.mu.Lock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.Unlock()
+
+	for _, s := range sessions {
+		resp, err := m.cli.ContainerStats(ctx, s.ContainerID, false)
+		if err != nil {
+			continue
+		}
+
+		var stats types.StatsJSON
+		err = json.NewDecoder(resp.Body).Decode(&stats)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
+		systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
+		var cpuPercent float64
+		if systemDelta > 0 && cpuDelta > 0 {
+			cpuPercent = (cpuDelta / systemDelta) * float64(len(stats.CPUStats.CPUUsage.PercpuUsage)) * 100
+		}
+
+		observability.ContainerResourceUsage.WithLabelValues(s.ID, "cpu_percent").Set(cpuPercent)
+		observability.ContainerResourceUsage.WithLabelValues(s.ID, "memory_bytes").Set(float64(stats.MemoryStats.Usage))
+	}
+}
+
+// reapOrphans force-stops any managed-by=thesis container this process has
+// no session for — the case after a backend restart, since AutoRemove
+// only cleans up containers the daemon itself stopped.
+func (m *SandboxManager) reapOrphans(ctx context.Context) {
+	managed, err := listManagedContainers(ctx, m.cli)
+	if err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	known := make(map[string]bool, len(m.sessions))
+	for _, s := range m.sessions {
+		known[s.ContainerID] = true
+	}
+	m.mu.Unlock()
+
+	for _, c := range managed {
+		if known[c.ID] {
+			continue
+		}
+		_ = m.cli.ContainerStop(ctx, c.ID, nil)
+		_ = m.cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true})
+
+		observability.ContainerStops.WithLabelValues("orphan_reaped").Inc()
+		observability.Log.Warn("reaped orphaned sandbox container",
+			zap.String("container_id", c.ID))
+	}
+}
@@ -0,0 +1,80 @@
+package containers
+
+import (
+	"sync"
+	"testing"
+)
+
+// newTestManager builds a SandboxManager with just enough state for
+// reserveSlot/releaseSlot - no Docker client, since those don't touch cli.
+func newTestManager(maxPerOwner int) *SandboxManager {
+	return &SandboxManager{
+		byOwner:     make(map[string]int),
+		maxPerOwner: maxPerOwner,
+	}
+}
+
+func TestReserveSlotEnforcesLimit(t *testing.T) {
+	m := newTestManager(2)
+
+	if !m.reserveSlot("owner-1") {
+		t.Fatal("first reservation should succeed")
+	}
+	if !m.reserveSlot("owner-1") {
+		t.Fatal("second reservation should succeed")
+	}
+	if m.reserveSlot("owner-1") {
+		t.Fatal("third reservation should be refused once at the limit")
+	}
+
+	m.releaseSlot("owner-1")
+	if !m.reserveSlot("owner-1") {
+		t.Fatal("reservation should succeed again after a release frees a slot")
+	}
+}
+
+func TestReserveSlotUnlimitedWhenZero(t *testing.T) {
+	m := newTestManager(0)
+
+	for i := 0; i < 10; i++ {
+		if !m.reserveSlot("owner-1") {
+			t.Fatalf("reservation %d should succeed with no limit configured", i)
+		}
+	}
+}
+
+// TestReserveSlotConcurrent starts far more concurrent reservations than
+// the limit allows and checks that exactly maxPerOwner of them win - the
+// TOCTOU this guards against would let multiple concurrent callers all
+// read the count before any of them incremented it, over-admitting.
+func TestReserveSlotConcurrent(t *testing.T) {
+	const maxPerOwner = 3
+	const attempts = 50
+
+	m := newTestManager(maxPerOwner)
+
+	var wg sync.WaitGroup
+	results := make([]bool, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = m.reserveSlot("owner-1")
+		}(i)
+	}
+	wg.Wait()
+
+	admitted := 0
+	for _, ok := range results {
+		if ok {
+			admitted++
+		}
+	}
+
+	if admitted != maxPerOwner {
+		t.Fatalf("admitted %d reservations, want exactly %d", admitted, maxPerOwner)
+	}
+	if m.byOwner["owner-1"] != maxPerOwner {
+		t.Fatalf("byOwner[owner-1] = %d, want %d", m.byOwner["owner-1"], maxPerOwner)
+	}
+}
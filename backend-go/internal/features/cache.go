@@ -0,0 +1,47 @@
+package features
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+// urlCache caches fetchMeta results keyed by hostname so a burst of checks
+// against the same domain only pays the WHOIS/TLS round trip once.
+type urlCache struct {
+	store *ristretto.Cache
+	ttl   time.Duration
+}
+
+func newURLCache(ttl time.Duration) *urlCache {
+	store, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 1e5,
+		MaxCost:     1 << 20,
+		BufferItems: 64,
+	})
+	if err != nil {
+		// A cache we can't build is not fatal: every lookup just misses.
+		store = nil
+	}
+
+	return &urlCache{store: store, ttl: ttl}
+}
+
+// Get returns the cached metadata for host, fetching and caching it on a
+// miss.
+func (c *urlCache) Get(ctx context.Context, host string) urlMeta {
+	if c.store != nil {
+		if v, ok := c.store.Get(host); ok {
+			return v.(urlMeta)
+		}
+	}
+
+	meta := fetchMeta(host)
+
+	if c.store != nil {
+		c.store.SetWithTTL(host, meta, 1, c.ttl)
+	}
+
+	return meta
+}
@@ -0,0 +1,156 @@
+// Package features extracts structured signals from phishing-check input
+// before it reaches the ML classifier: lexical properties of any URLs,
+// their WHOIS/TLS metadata, and — for attached HTML — signs of a credential
+// harvesting form. The result is a flat feature vector the classifier can
+// consume alongside the raw text, plus a verdict per URL.
+package features
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// Verdict is the per-URL assessment produced while extracting features.
+type Verdict struct {
+	Suspicious bool     `json:"suspicious"`
+	Score      float64  `json:"score"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// Vector is the flat, classifier-ready feature vector extracted from a
+// phishing check's URLs and HTML. URLs are taken only from the request's
+// URL list; they are not parsed out of the free text.
+type Vector map[string]float64
+
+// Result bundles the extracted feature vector with a verdict for every
+// URL found in the input.
+type Result struct {
+	Vector   Vector
+	Verdicts map[string]Verdict
+}
+
+const (
+	keyURLCount         = "url_count"
+	keyAvgURLLength     = "avg_url_length"
+	keyMaxEntropy        = "max_entropy"
+	keyHasPunycode       = "has_punycode"
+	keyMaxSubdomains     = "max_subdomain_count"
+	keyHasSuspiciousTLD  = "has_suspicious_tld"
+	keyMaxHomoglyphScore = "max_homoglyph_score"
+	keyMinDomainAgeDays  = "min_domain_age_days"
+	keyAnyCertInvalid    = "any_cert_invalid"
+	keyHiddenForms       = "html_hidden_forms"
+	keyObfuscatedJS      = "html_obfuscated_js"
+	keyCrossOriginForms  = "html_cross_origin_forms"
+)
+
+// Extractor extracts features for phishing checks, caching per-URL network
+// lookups (WHOIS age, TLS certificate metadata) so repeat checks against
+// the same domain don't re-fetch them.
+type Extractor struct {
+	cache *urlCache
+}
+
+// NewExtractor builds an Extractor whose per-URL lookup cache entries
+// expire after ttl.
+func NewExtractor(ttl time.Duration) *Extractor {
+	return &Extractor{cache: newURLCache(ttl)}
+}
+
+// Extract parses urls and html out of a phishing check, computes lexical,
+// WHOIS, TLS, and DOM features for each, and returns the combined feature
+// vector plus a verdict per URL.
+func (e *Extractor) Extract(ctx context.Context, urls []string, html string) Result {
+	result := Result{
+		Vector:   make(Vector),
+		Verdicts: make(map[string]Verdict),
+	}
+
+	result.Vector[keyURLCount] = float64(len(urls))
+
+	var totalLength float64
+	for i, raw := range urls {
+		verdict, length := e.extractURL(ctx, raw, result.Vector)
+		result.Verdicts[raw] = verdict
+		totalLength += length
+		result.Vector[keyAvgURLLength] = totalLength / float64(i+1)
+	}
+
+	if html != "" {
+		extractHTML(html, result.Vector)
+	}
+
+	return result
+}
+
+// extractURL resolves raw (following known shorteners), computes its
+// lexical/WHOIS/TLS features, folds the interesting ones into vec as a
+// running max/min across every URL seen, and returns its verdict and URL
+// length (so the caller can track the running average length).
+func (e *Extractor) extractURL(ctx context.Context, raw string, vec Vector) (Verdict, float64) {
+	resolved, err := resolveShortener(ctx, raw)
+	if err != nil {
+		resolved = raw
+	}
+
+	parsed, err := url.Parse(resolved)
+	if err != nil {
+		return Verdict{Suspicious: true, Score: 1, Reasons: []string{"unparseable URL"}}, float64(len(raw))
+	}
+
+	lex := lexicalFeatures(resolved)
+	vec[keyMaxEntropy] = max64(vec[keyMaxEntropy], lex.entropy)
+	vec[keyMaxSubdomains] = max64(vec[keyMaxSubdomains], float64(lex.subdomainCount))
+	vec[keyMaxHomoglyphScore] = max64(vec[keyMaxHomoglyphScore], lex.homoglyphScore)
+	if lex.hasPunycode {
+		vec[keyHasPunycode] = 1
+	}
+	if lex.suspiciousTLD {
+		vec[keyHasSuspiciousTLD] = 1
+	}
+
+	reasons := lex.reasons()
+	score := lex.score()
+
+	host := parsed.Hostname()
+	if host != "" {
+		meta := e.cache.Get(ctx, host)
+		if meta.domainAgeDays >= 0 {
+			if vec[keyMinDomainAgeDays] == 0 {
+				vec[keyMinDomainAgeDays] = meta.domainAgeDays
+			} else {
+				vec[keyMinDomainAgeDays] = min64(vec[keyMinDomainAgeDays], meta.domainAgeDays)
+			}
+			if meta.domainAgeDays < 30 {
+				reasons = append(reasons, "domain registered under 30 days ago")
+				score += 0.2
+			}
+		}
+		if meta.certInvalid {
+			vec[keyAnyCertInvalid] = 1
+			reasons = append(reasons, "TLS certificate invalid or self-signed")
+			score += 0.2
+		}
+	}
+
+	if score > 1 {
+		score = 1
+	}
+
+	return Verdict{Suspicious: score >= 0.5, Score: score, Reasons: reasons}, lex.length
+}
+
+func max64(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min64(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,114 @@
+package features
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// obfuscationMarkers are script patterns common to obfuscated/packed
+// JavaScript (eval-based packers, hex/unicode escape floods) rather than
+// hand-written code.
+var obfuscationMarkers = []string{"eval(", "unescape(", "fromCharCode", "document.write(unescape"}
+
+// extractHTML walks doc for signs of a credential-harvesting page: forms
+// hidden from the visible page, obfuscated inline scripts, and forms that
+// post to a different origin than the page itself, folding counts into
+// vec. The page's own origin is taken from its <base href>; a document
+// without one has no form counted as cross-origin.
+func extractHTML(doc string, vec Vector) {
+	node, err := html.Parse(strings.NewReader(doc))
+	if err != nil {
+		return
+	}
+
+	var pageOrigin string
+	var hiddenForms, obfuscatedScripts, crossOriginForms float64
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "base":
+				if href := attr(n, "href"); href != "" && pageOrigin == "" {
+					pageOrigin = hostname(href)
+				}
+			case "form":
+				if isHidden(n) {
+					hiddenForms++
+				}
+				if action := attr(n, "action"); action != "" {
+					if target := hostname(action); target != "" && pageOrigin != "" && target != pageOrigin {
+						crossOriginForms++
+					}
+				}
+			case "script":
+				if containsObfuscation(textContent(n)) {
+					obfuscatedScripts++
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+
+	vec[keyHiddenForms] = hiddenForms
+	vec[keyObfuscatedJS] = obfuscatedScripts
+	vec[keyCrossOriginForms] = crossOriginForms
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func isHidden(n *html.Node) bool {
+	style := strings.ToLower(attr(n, "style"))
+	if strings.Contains(style, "display:none") || strings.Contains(style, "display: none") ||
+		strings.Contains(style, "visibility:hidden") || strings.Contains(style, "visibility: hidden") {
+		return true
+	}
+	if _, ok := attrExists(n, "hidden"); ok {
+		return true
+	}
+	return false
+}
+
+func attrExists(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func containsObfuscation(script string) bool {
+	for _, marker := range obfuscationMarkers {
+		if strings.Contains(script, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package features
+
+import (
+	"math"
+	"strings"
+)
+
+// suspiciousTLDs are TLDs disproportionately used for disposable phishing
+// domains. Not exhaustive — a starting list to flag, not to block on.
+var suspiciousTLDs = map[string]bool{
+	"zip": true, "mov": true, "top": true, "xyz": true,
+	"tk": true, "ml": true, "ga": true, "cf": true, "gq": true,
+}
+
+// lexicalResult holds the per-URL lexical signals computed from the raw
+// URL string alone, with no network access.
+type lexicalResult struct {
+	length         float64
+	entropy        float64
+	hasPunycode    bool
+	subdomainCount int
+	suspiciousTLD  bool
+	homoglyphScore float64
+}
+
+func (l lexicalResult) reasons() []string {
+	var reasons []string
+	if l.hasPunycode {
+		reasons = append(reasons, "punycode-encoded hostname")
+	}
+	if l.suspiciousTLD {
+		reasons = append(reasons, "suspicious top-level domain")
+	}
+	if l.subdomainCount >= 3 {
+		reasons = append(reasons, "excessive subdomains")
+	}
+	if l.homoglyphScore > 0.3 {
+		reasons = append(reasons, "hostname resembles a popular domain (homoglyphs)")
+	}
+	if l.entropy > 4.2 {
+		reasons = append(reasons, "high entropy hostname")
+	}
+	return reasons
+}
+
+func (l lexicalResult) score() float64 {
+	var score float64
+	if l.hasPunycode {
+		score += 0.3
+	}
+	if l.suspiciousTLD {
+		score += 0.2
+	}
+	if l.subdomainCount >= 3 {
+		score += 0.15
+	}
+	score += l.homoglyphScore * 0.3
+	if l.entropy > 4.2 {
+		score += 0.15
+	}
+	return score
+}
+
+// lexicalFeatures computes lexical signals for rawURL without making any
+// network calls.
+func lexicalFeatures(rawURL string) lexicalResult {
+	host := hostname(rawURL)
+
+	return lexicalResult{
+		length:         float64(len(rawURL)),
+		entropy:        shannonEntropy(host),
+		hasPunycode:    strings.Contains(host, "xn--"),
+		subdomainCount: subdomainCount(host),
+		suspiciousTLD:  isSuspiciousTLD(host),
+		homoglyphScore: homoglyphScore(host),
+	}
+}
+
+// hostname extracts the host portion of rawURL, tolerating URLs missing a
+// scheme (net/url.Parse would otherwise treat the whole string as a path).
+func hostname(rawURL string) string {
+	s := rawURL
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	if i := strings.IndexAny(s, "/?#"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		s = s[i+1:]
+	}
+	if i := strings.Index(s, ":"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ToLower(s)
+}
+
+func subdomainCount(host string) int {
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return 0
+	}
+	return len(labels) - 2
+}
+
+func isSuspiciousTLD(host string) bool {
+	labels := strings.Split(host, ".")
+	if len(labels) == 0 {
+		return false
+	}
+	return suspiciousTLDs[labels[len(labels)-1]]
+}
+
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	var entropy float64
+	n := float64(len(s))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// popularDomains are brands commonly impersonated via homoglyph lookalike
+// hostnames.
+var popularDomains = []string{
+	"paypal.com", "google.com", "microsoft.com", "apple.com",
+	"amazon.com", "facebook.com", "bankofamerica.com",
+}
+
+// homoglyphScore returns how close host's registrable label is, edit-
+// distance-wise, to a well-known domain it is not actually equal to — a
+// cheap proxy for "looks like paypa1.com" without a full homoglyph table.
+// It compares labels rather than full hostnames so "mail.google.com"
+// isn't penalized for resembling "google.com" itself, and it only flags a
+// small (1-2 character) edit distance — similarity ratios over the whole
+// string flag unrelated brands (e.g. google.com vs apple.com) as
+// lookalikes, which they aren't.
+func homoglyphScore(host string) float64 {
+	label := registrableLabel(host)
+	if label == "" {
+		return 0
+	}
+
+	for _, popular := range popularDomains {
+		if label == registrableLabel(popular) {
+			// host itself is one of the known-good domains (possibly
+			// under a subdomain) - not a lookalike of anything.
+			return 0
+		}
+	}
+
+	best := 0.0
+	for _, popular := range popularDomains {
+		dist := levenshtein(label, registrableLabel(popular))
+		if dist < 1 || dist > 2 {
+			continue
+		}
+		// dist=1 ("paypa1" vs "paypal") is a stronger signal than dist=2.
+		score := 1.0 - float64(dist-1)*0.5
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// registrableLabel returns the second-to-last label of host, e.g.
+// "google" for "www.google.com" - the part a homoglyph attack actually
+// spoofs, ignoring subdomains and the TLD itself. Multi-label public
+// suffixes are not recognised: "example.co.uk" yields "co".
+func registrableLabel(host string) string {
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return host
+	}
+	return labels[len(labels)-2]
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
@@ -0,0 +1,138 @@
+package features
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// urlMeta is the per-domain network metadata fetched (and cached) while
+// extracting features.
+type urlMeta struct {
+	domainAgeDays float64 // -1 if unknown
+	certInvalid   bool
+}
+
+// knownShorteners are resolved to their final destination before lexical
+// analysis, since a shortener's own hostname carries no signal.
+var knownShorteners = map[string]bool{
+	"bit.ly": true, "tinyurl.com": true, "t.co": true,
+	"goo.gl": true, "ow.ly": true, "is.gd": true,
+}
+
+// resolveShortener follows a single HTTP redirect if rawURL's host is a
+// known shortener, returning rawURL unchanged otherwise.
+func resolveShortener(ctx context.Context, rawURL string) (string, error) {
+	host := hostname(rawURL)
+	if !knownShorteners[host] {
+		return rawURL, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		return rawURL, err
+	}
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return rawURL, err
+	}
+	defer resp.Body.Close()
+
+	if loc := resp.Header.Get("Location"); loc != "" {
+		return loc, nil
+	}
+	return rawURL, nil
+}
+
+// fetchMeta performs the live WHOIS age and TLS certificate lookups for
+// host. It is only called on a urlCache miss.
+func fetchMeta(host string) urlMeta {
+	return urlMeta{
+		domainAgeDays: whoisDomainAgeDays(host),
+		certInvalid:   !tlsCertValid(host),
+	}
+}
+
+var whoisCreationRe = regexp.MustCompile(`(?i)creation date:\s*(\S+)`)
+
+// whoisDomainAgeDays queries the registrar's WHOIS server (port 43) for
+// host's creation date and returns its age in days, or -1 if the lookup
+// failed or the reply didn't contain a parseable creation date.
+func whoisDomainAgeDays(host string) float64 {
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return -1
+	}
+	tld := labels[len(labels)-1]
+
+	conn, err := net.DialTimeout("tcp", tld+".whois-servers.net:43", 5*time.Second)
+	if err != nil {
+		return -1
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := fmt.Fprintf(conn, "%s\r\n", host); err != nil {
+		return -1
+	}
+
+	// WHOIS replies are commonly several KB and arrive over multiple TCP
+	// segments, with the creation date line often past the first read -
+	// read to EOF (the deadline above bounds it) rather than one Read.
+	reply, err := io.ReadAll(conn)
+	if err != nil && len(reply) == 0 {
+		return -1
+	}
+
+	match := whoisCreationRe.FindSubmatch(reply)
+	if match == nil {
+		return -1
+	}
+
+	created, err := time.Parse(time.RFC3339, strings.TrimSpace(string(match[1])))
+	if err != nil {
+		return -1
+	}
+
+	return time.Since(created).Hours() / 24
+}
+
+// tlsCertValid dials host:443 and reports whether its certificate chain
+// verifies. Any connection failure is treated as "couldn't verify" rather
+// than "invalid", since a down or non-HTTPS host isn't itself a phishing
+// signal.
+func tlsCertValid(host string) bool {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", host+":443", &tls.Config{
+		ServerName: host,
+	})
+	if err != nil {
+		// A handshake that failed certificate verification is an invalid
+		// certificate; any other failure is "couldn't verify".
+		var unknownAuthority x509.UnknownAuthorityError
+		var certInvalid x509.CertificateInvalidError
+		var hostnameErr x509.HostnameError
+		if errors.As(err, &unknownAuthority) || errors.As(err, &certInvalid) || errors.As(err, &hostnameErr) {
+			return false
+		}
+		return true
+	}
+	defer conn.Close()
+
+	return len(conn.ConnectionState().VerifiedChains) > 0
+}
@@ -0,0 +1,74 @@
+package mlclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sony/gobreaker"
+)
+
+// Client is the Predictor the rest of the backend talks to. It wraps the
+// configured primary backend in a circuit breaker and falls back to a
+// local predictor (ONNX) whenever the breaker is open, so a dead ML
+// service degrades gracefully instead of returning 500s.
+type Client struct {
+	primary  Predictor
+	fallback Predictor
+	breaker  *gobreaker.CircuitBreaker
+
+	mu      sync.RWMutex
+	serving string
+}
+
+// NewClient wraps primary in a circuit breaker that trips over to
+// fallback after repeated consecutive failures. fallback may be nil, in
+// which case the breaker's error is returned: primary's own error, or
+// gobreaker.ErrOpenState while the breaker is open.
+func NewClient(primary, fallback Predictor) *Client {
+	c := &Client{primary: primary, fallback: fallback, serving: primary.Name()}
+
+	c.breaker = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name: "mlclient-" + primary.Name(),
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 3
+		},
+	})
+
+	return c
+}
+
+func (c *Client) Name() string { return "breaker(" + c.primary.Name() + ")" }
+
+// Predict tries the primary backend through the circuit breaker and
+// transparently serves from fallback if the breaker is open or the call
+// fails.
+func (c *Client) Predict(ctx context.Context, text string, features map[string]float64) (Score, error) {
+	result, err := c.breaker.Execute(func() (interface{}, error) {
+		return c.primary.Predict(ctx, text, features)
+	})
+	if err == nil {
+		c.setServing(c.primary.Name())
+		return result.(Score), nil
+	}
+
+	if c.fallback == nil {
+		return Score{}, err
+	}
+
+	c.setServing(c.fallback.Name())
+	return c.fallback.Predict(ctx, text, features)
+}
+
+// Serving reports which backend served the most recent prediction, for
+// the health-check endpoint.
+func (c *Client) Serving() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.serving
+}
+
+func (c *Client) setServing(name string) {
+	c.mu.Lock()
+	c.serving = name
+	c.mu.Unlock()
+}
@@ -0,0 +1,113 @@
+package mlclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	inference "github.com/kserve/open-inference-protocol/gen/go/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// grpcPredictor talks to a KServe/Triton-style inference server over the
+// Open Inference Protocol (V2) gRPC API.
+type grpcPredictor struct {
+	client    inference.GRPCInferenceServiceClient
+	modelName string
+}
+
+// NewGRPCPredictor dials target (a KServe/Triton gRPC endpoint) and
+// returns a Predictor that runs modelName for every call.
+func NewGRPCPredictor(target, modelName string) (*grpcPredictor, error) {
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("dialing inference server %s: %w", target, err)
+	}
+
+	return &grpcPredictor{
+		client:    inference.NewGRPCInferenceServiceClient(conn),
+		modelName: modelName,
+	}, nil
+}
+
+func (p *grpcPredictor) Name() string { return "grpc" }
+
+func (p *grpcPredictor) Predict(ctx context.Context, text string, features map[string]float64) (Score, error) {
+	inputs := []*inference.ModelInferRequest_InferInputTensor{
+		{
+			Name:     "text",
+			Datatype: "BYTES",
+			Shape:    []int64{1},
+			Contents: &inference.InferTensorContents{
+				BytesContents: [][]byte{[]byte(text)},
+			},
+		},
+	}
+
+	if len(features) > 0 {
+		inputs = append(inputs, &inference.ModelInferRequest_InferInputTensor{
+			Name:     "features",
+			Datatype: "FP32",
+			Shape:    []int64{1, int64(len(features))},
+			Contents: &inference.InferTensorContents{
+				Fp32Contents: featureValues(features),
+			},
+		})
+	}
+
+	req := &inference.ModelInferRequest{
+		ModelName: p.modelName,
+		Inputs:    inputs,
+	}
+
+	resp, err := p.client.ModelInfer(ctx, req)
+	if err != nil {
+		return Score{}, err
+	}
+
+	return scoreFromInferResponse(resp)
+}
+
+// featureValues flattens a feature map into a slice with a stable key
+// order, since map iteration order is random and the model expects a
+// consistent input layout.
+func featureValues(features map[string]float64) []float32 {
+	keys := make([]string, 0, len(features))
+	for k := range features {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]float32, len(keys))
+	for i, k := range keys {
+		values[i] = float32(features[k])
+	}
+	return values
+}
+
+// scoreFromInferResponse decodes the FP32 phishing_probability output
+// tensor from a Triton/KServe inference response into a Score, deriving
+// the prediction label by thresholding the probability at 0.5. Any other
+// output tensors are ignored.
+func scoreFromInferResponse(resp *inference.ModelInferResponse) (Score, error) {
+	for _, out := range resp.Outputs {
+		if out.Name != "phishing_probability" {
+			continue
+		}
+		contents := out.Contents.GetFp32Contents()
+		if len(contents) == 0 {
+			return Score{}, fmt.Errorf("mlclient: empty %s tensor in inference response", out.Name)
+		}
+
+		probability := float64(contents[0])
+		prediction := "legit"
+		if probability >= 0.5 {
+			prediction = "phishing"
+		}
+
+		return Score{PhishingProbability: probability, Prediction: prediction}, nil
+	}
+
+	return Score{}, fmt.Errorf("mlclient: inference response missing phishing_probability output")
+}
@@ -0,0 +1,65 @@
+package mlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/observability"
+)
+
+// predictRequestBody is the wire format sent to the ML inference service:
+// the raw text plus the feature vector internal/features extracted, if
+// any.
+type predictRequestBody struct {
+	Text     string             `json:"text"`
+	Features map[string]float64 `json:"features,omitempty"`
+}
+
+// httpPredictor is the original backend: a plain JSON POST to the ML
+// inference service.
+type httpPredictor struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPPredictor(url string) *httpPredictor {
+	return &httpPredictor{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *httpPredictor) Name() string { return "http" }
+
+func (p *httpPredictor) Predict(ctx context.Context, text string, features map[string]float64) (Score, error) {
+	ctx, span := observability.StartSpan(ctx, "mlclient.http.Predict")
+	defer span.End()
+
+	reqBody, _ := json.Marshal(predictRequestBody{Text: text, Features: features})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return Score{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Score{}, err
+	}
+	defer resp.Body.Close()
+
+	var result models.PhishingCheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Score{}, err
+	}
+
+	return Score{
+		PhishingProbability: result.PhishingProbability,
+		Prediction:          result.Prediction,
+	}, nil
+}
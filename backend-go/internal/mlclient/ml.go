@@ -1,27 +1,202 @@
 package mlclient
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
+	"context"
+	"log"
+	"os"
+	"sync"
+
 	"backend/internal/models"
+	"backend/internal/observability"
+
+	"github.com/gorilla/websocket"
+)
+
+// Backend selects which Predictor implementation backs phishing checks.
+type Backend string
+
+const (
+	BackendHTTP Backend = "http"
+	BackendGRPC Backend = "grpc"
+	BackendONNX Backend = "onnx"
+)
+
+const (
+	predictURL       = "http://ml-inference:8080/predict"
+	predictStreamURL = "ws://ml-inference:8080/predict/stream"
+)
+
+var (
+	defaultClient *Client
+	configureOnce sync.Once
+	configureErr  error
 )
 
-func PredictPhishing(text string) (models.PhishingCheckResponse, error) {
-	var result models.PhishingCheckResponse
-	reqBody, _ := json.Marshal(models.PhishingCheckRequest{Text:text})
+// Configure builds the package-level Client used by PredictPhishing from
+// the ML_BACKEND environment variable (http, grpc, or onnx; default
+// http), wrapped in a circuit breaker that falls back to the local ONNX
+// model whenever the primary backend is unreachable. It is safe to call
+// more than once (and concurrently) - only the first call does any work.
+// Callers should invoke this once at startup; PredictPhishing also calls
+// it lazily for callers that forget to.
+func Configure() error {
+	configureOnce.Do(func() {
+		configureErr = configure()
+	})
+	return configureErr
+}
+
+func configure() error {
+	onnxModelPath := envOr("ML_ONNX_MODEL_PATH", "/models/phishing.onnx")
+
+	// onnx is built at most once: when ML_BACKEND=onnx it serves as both
+	// primary and fallback (so the breaker has nothing useful to fall
+	// back to, same as any other backend with no fallback configured),
+	// rather than loading the model and initializing the ONNX runtime
+	// twice.
+	var onnx *onnxPredictor
+	loadONNX := func() (*onnxPredictor, error) {
+		if onnx != nil {
+			return onnx, nil
+		}
+		p, err := NewONNXPredictor(onnxModelPath)
+		if err != nil {
+			return nil, err
+		}
+		onnx = p
+		return onnx, nil
+	}
+
+	var primary Predictor
+
+	switch Backend(os.Getenv("ML_BACKEND")) {
+	case BackendGRPC:
+		p, err := NewGRPCPredictor(envOr("ML_GRPC_TARGET", "ml-inference:9090"), envOr("ML_MODEL_NAME", "phishing-classifier"))
+		if err != nil {
+			return err
+		}
+		primary = p
+	case BackendONNX:
+		p, err := loadONNX()
+		if err != nil {
+			return err
+		}
+		primary = p
+	default:
+		primary = newHTTPPredictor(envOr("ML_HTTP_URL", predictURL))
+	}
+
+	// The ONNX fallback is best-effort: if the native runtime or model
+	// file isn't available in this environment, serve the primary
+	// backend alone rather than failing every request over a missing
+	// nice-to-have.
+	fallback, err := loadONNX()
+	if err != nil {
+		log.Printf("mlclient: onnx fallback unavailable, continuing without it: %v", err)
+		fallback = nil
+	}
+	var fallbackPredictor Predictor
+	if fallback != nil {
+		fallbackPredictor = fallback
+	}
+
+	defaultClient = NewClient(primary, fallbackPredictor)
+	return nil
+}
 
-	resp, err := http.Post("http://ml-inference:8080/predict", "application/json", bytes.NewBuffer(reqBody))
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// PredictPhishing runs the configured backend on text plus the extracted
+// feature vector (nil if the request had none), adapting the result to
+// the PhishingCheckResponse shape the handler returns to clients.
+func PredictPhishing(ctx context.Context, text string, features map[string]float64) (models.PhishingCheckResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "mlclient.PredictPhishing")
+	defer span.End()
+
+	// Configure is idempotent (guarded by configureOnce), so calling it
+	// here is cheap after the first time and protects callers - such as
+	// tests - that hit this before main has configured the client.
+	if err := Configure(); err != nil {
+		return models.PhishingCheckResponse{}, err
+	}
 
+	score, err := defaultClient.Predict(ctx, text, features)
 	if err != nil {
-		return result, err 
+		return models.PhishingCheckResponse{}, err
 	}
 
-	defer resp.Body.Close()
+	return models.PhishingCheckResponse{
+		PhishingProbability: score.PhishingProbability,
+		Prediction:          score.Prediction,
+	}, nil
+}
 
-	if err := json.NewDecoder(resp.Body).Decode((&result)); err != nil {
-		return result, err
+// ServingBackend reports which Predictor implementation is currently
+// serving phishing checks, for the health-check endpoint.
+func ServingBackend() string {
+	if defaultClient == nil {
+		return "unconfigured"
 	}
+	return defaultClient.Serving()
+}
 
-	return result, nil 
-}
\ No newline at end of file
+var streamDialer = websocket.Dialer{}
+
+// StreamPredictPhishing proxies an already-upgraded client WebSocket
+// connection to the ML inference service's streaming endpoint. Client
+// chunks are forwarded upstream as they arrive, and partial/final
+// PhishingCheckStreamResponse messages are relayed back. It returns once
+// ctx is cancelled or either side closes the connection.
+func StreamPredictPhishing(ctx context.Context, clientConn *websocket.Conn) error {
+	upstreamConn, _, err := streamDialer.DialContext(ctx, predictStreamURL, nil)
+	if err != nil {
+		return err
+	}
+	defer upstreamConn.Close()
+
+	done := make(chan error, 2)
+
+	go func() {
+		for {
+			msgType, data, err := clientConn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			if err := upstreamConn.WriteMessage(msgType, data); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			var resp models.PhishingCheckStreamResponse
+			if err := upstreamConn.ReadJSON(&resp); err != nil {
+				done <- err
+				return
+			}
+			if err := clientConn.WriteJSON(resp); err != nil {
+				done <- err
+				return
+			}
+			if resp.Final {
+				done <- nil
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
@@ -0,0 +1,106 @@
+package mlclient
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// The bundled phishing classifier (models/phishing.onnx) takes a single
+// fixed-length input: a hashed bag-of-characters vector over textDim
+// slots, followed by featureDim slots for the internal/features vector,
+// hashed the same way so the model doesn't need a fixed feature schema.
+const (
+	onnxTextDim    = 224
+	onnxFeatureDim = 32
+	onnxInputDim   = onnxTextDim + onnxFeatureDim
+)
+
+// onnxPredictor runs the phishing classifier locally via ONNX Runtime.
+// It exists as the circuit breaker's fallback: when the remote ML service
+// (HTTP or gRPC) is unreachable, checks still get scored instead of
+// failing outright.
+type onnxPredictor struct {
+	mu      sync.Mutex
+	session *ort.AdvancedSession
+	input   *ort.Tensor[float32]
+	output  *ort.Tensor[float32]
+}
+
+// NewONNXPredictor loads the phishing classifier from modelPath and
+// prepares a reusable inference session.
+func NewONNXPredictor(modelPath string) (*onnxPredictor, error) {
+	if err := ort.InitializeEnvironment(); err != nil {
+		return nil, fmt.Errorf("initializing onnxruntime: %w", err)
+	}
+
+	input, err := ort.NewEmptyTensor[float32](ort.NewShape(1, onnxInputDim))
+	if err != nil {
+		return nil, fmt.Errorf("allocating onnx input tensor: %w", err)
+	}
+
+	output, err := ort.NewEmptyTensor[float32](ort.NewShape(1, 1))
+	if err != nil {
+		return nil, fmt.Errorf("allocating onnx output tensor: %w", err)
+	}
+
+	session, err := ort.NewAdvancedSession(modelPath,
+		[]string{"input"}, []string{"phishing_probability"},
+		[]ort.ArbitraryTensor{input}, []ort.ArbitraryTensor{output}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading onnx model %s: %w", modelPath, err)
+	}
+
+	return &onnxPredictor{session: session, input: input, output: output}, nil
+}
+
+func (p *onnxPredictor) Name() string { return "onnx" }
+
+func (p *onnxPredictor) Predict(ctx context.Context, text string, features map[string]float64) (Score, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data := p.input.GetData()
+	copy(data[:onnxTextDim], vectorizeText(text))
+	copy(data[onnxTextDim:], vectorizeFeatures(features))
+
+	if err := p.session.Run(); err != nil {
+		return Score{}, fmt.Errorf("onnx inference: %w", err)
+	}
+
+	probability := float64(p.output.GetData()[0])
+	prediction := "legit"
+	if probability >= 0.5 {
+		prediction = "phishing"
+	}
+
+	return Score{PhishingProbability: probability, Prediction: prediction}, nil
+}
+
+// vectorizeText turns text into a fixed-length hashed bag-of-characters
+// vector, a cheap stand-in for the tokenizer the model was trained with.
+func vectorizeText(text string) []float32 {
+	vec := make([]float32, onnxTextDim)
+	for _, r := range text {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(string(r)))
+		vec[h.Sum32()%onnxTextDim]++
+	}
+	return vec
+}
+
+// vectorizeFeatures hashes the internal/features vector into a fixed-size
+// slot range, the same trick vectorizeText uses, so the model's input
+// layout doesn't depend on which feature keys were actually computed.
+func vectorizeFeatures(features map[string]float64) []float32 {
+	vec := make([]float32, onnxFeatureDim)
+	for k, v := range features {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(k))
+		vec[h.Sum32()%onnxFeatureDim] += float32(v)
+	}
+	return vec
+}
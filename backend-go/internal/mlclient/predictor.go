@@ -0,0 +1,20 @@
+package mlclient
+
+import "context"
+
+// Score is the verdict a Predictor produces for a single piece of text.
+type Score struct {
+	PhishingProbability float64
+	Prediction          string
+}
+
+// Predictor is implemented by every phishing-classification backend: the
+// HTTP ML service, a gRPC inference server, and the local ONNX fallback.
+type Predictor interface {
+	// Predict returns a phishing Score for text plus its extracted
+	// feature vector (nil if none was computed), or an error if the
+	// backend could not be reached or failed to respond.
+	Predict(ctx context.Context, text string, features map[string]float64) (Score, error)
+	// Name identifies the backend, e.g. for health-check reporting.
+	Name() string
+}
@@ -1,10 +1,47 @@
 package models
 
+import "backend/internal/features"
+
 type PhishingCheckRequest struct {
 	Text string `json: "text" binding: "required"`
+	// URLs and HTML are optional: when present, the handler runs them
+	// through internal/features before classification so the model sees
+	// lexical/WHOIS/TLS/DOM signals alongside the raw text.
+	URLs []string `json:"urls,omitempty"`
+	HTML string   `json:"html,omitempty"`
+	// UserID identifies the caller for the audit log. There is no auth
+	// layer in front of this endpoint yet (same as ContainerStartRequest's
+	// OwnerID), so it is caller-reported rather than derived from a
+	// session; left blank, the audit entry simply has no user.
+	UserID string `json:"user_id,omitempty"`
+	// SessionID links this check to the sandbox session it was triggered
+	// from, if any (e.g. a browser container checking a page it navigated
+	// to). Left blank for standalone checks outside a sandbox session.
+	SessionID string `json:"session_id,omitempty"`
 }
 
 type PhishingCheckResponse struct {
 	PhishingProbability float64 `json: "phishing_probability"`
 	Prediction string `json:"prediction"`
+	// Features and URLVerdicts are populated only when the request
+	// included URLs or HTML.
+	Features    features.Vector            `json:"features,omitempty"`
+	URLVerdicts map[string]features.Verdict `json:"url_verdicts,omitempty"`
+}
+
+// PhishingCheckStreamResponse is sent over the phishing-check WebSocket
+// stream once per processed chunk. Cumulative reflects the model's verdict
+// over all chunks received so far; Final marks the last message for a
+// given connection.
+type PhishingCheckStreamResponse struct {
+	Chunk      string                `json:"chunk"`
+	Cumulative PhishingCheckResponse `json:"cumulative"`
+	Final      bool                  `json:"final"`
+}
+
+// ContainerStartRequest is the payload for starting a disposable browser
+// container. OwnerID identifies the caller for per-owner concurrency
+// limits; the session itself is assigned an ID by the sandbox manager.
+type ContainerStartRequest struct {
+	OwnerID string `json:"owner_id" binding:"required"`
 }
\ No newline at end of file
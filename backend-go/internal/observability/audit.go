@@ -0,0 +1,67 @@
+package observability
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuditEntry is one line of the append-only phishing/session audit log,
+// suitable for later SIEM ingestion.
+type AuditEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	User        string    `json:"user,omitempty"`
+	SessionID   string    `json:"session_id,omitempty"`
+	URLChecked  string    `json:"url_checked,omitempty"`
+	Verdict     string    `json:"verdict,omitempty"`
+	ContainerID string    `json:"container_id,omitempty"`
+}
+
+var (
+	auditMu   sync.Mutex
+	auditFile *os.File
+)
+
+func initAudit(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	auditFile = f
+	return nil
+}
+
+// Audit appends entry to the audit log as one JSON line, stamping its
+// timestamp. It is a no-op if Init was never called with an audit log
+// path.
+func Audit(entry AuditEntry) {
+	if auditFile == nil {
+		return
+	}
+	entry.Timestamp = time.Now()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		Log.Warn("observability: failed to marshal audit entry", zap.Error(err))
+		return
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	_, _ = auditFile.Write(append(line, '\n'))
+}
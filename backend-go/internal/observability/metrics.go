@@ -0,0 +1,64 @@
+package observability
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// PhishingCheckDuration tracks handler-to-ML-response latency, labelled
+	// by which mlclient backend served the request.
+	PhishingCheckDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "thesis_phishing_check_duration_seconds",
+		Help:    "Latency of phishing-check requests, from handler entry to ML response.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"backend"})
+
+	// PhishingCheckRequestSize tracks how large the submitted text is.
+	PhishingCheckRequestSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "thesis_phishing_check_request_bytes",
+		Help:    "Size in bytes of the text submitted to a phishing check.",
+		Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+	})
+
+	// PhishingScore tracks the distribution of model phishing-probability
+	// scores.
+	PhishingScore = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "thesis_phishing_score",
+		Help:    "Model phishing-probability scores.",
+		Buckets: prometheus.LinearBuckets(0, 0.1, 11),
+	})
+
+	// PhishingPredictions counts predictions by label (phishing/legit).
+	PhishingPredictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "thesis_phishing_predictions_total",
+		Help: "Phishing-check predictions, by label.",
+	}, []string{"prediction"})
+
+	// ContainerStarts counts every browser sandbox container started.
+	ContainerStarts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "thesis_container_starts_total",
+		Help: "Browser sandbox containers started.",
+	})
+
+	// ContainerStops counts every browser sandbox container stopped, by
+	// reason (requested, ttl_expired, orphan_reaped).
+	ContainerStops = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "thesis_container_stops_total",
+		Help: "Browser sandbox containers stopped, by reason.",
+	}, []string{"reason"})
+
+	// ContainerResourceUsage holds the last cli.ContainerStats sample for
+	// each active session, by resource (cpu_percent, memory_bytes).
+	ContainerResourceUsage = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "thesis_container_resource_usage",
+		Help: "Last sampled resource usage per active session.",
+	}, []string{"session_id", "resource"})
+)
+
+// RegisterMetricsRoute mounts the Prometheus scrape endpoint on router.
+func RegisterMetricsRoute(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+}
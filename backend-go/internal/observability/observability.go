@@ -0,0 +1,36 @@
+// Package observability wires structured logging, Prometheus metrics, an
+// append-only audit log, and OpenTelemetry tracing into the backend, so
+// phishing checks and sandbox sessions are actually operable instead of
+// silent.
+package observability
+
+import "go.uber.org/zap"
+
+// Log is the process-wide structured logger. It is a no-op logger until
+// Init is called, so packages can log before main wires everything up
+// without guarding every call site with a nil check.
+var Log *zap.Logger = zap.NewNop()
+
+// Init replaces Log with a production JSON logger, opens the audit log
+// at auditLogPath (a no-op if empty), and registers the OTel tracer
+// provider so handler -> mlclient -> HTTP spans actually export
+// somewhere. Call it once from main before registering routes.
+func Init(auditLogPath string) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	Log = logger
+
+	if err := initAudit(auditLogPath); err != nil {
+		return err
+	}
+
+	if err := initTracing(); err != nil {
+		// Tracing is observability, not a serving dependency: a
+		// missing collector shouldn't stop the server from starting.
+		Log.Warn("observability: tracing disabled, continuing without it", zap.Error(err))
+	}
+
+	return nil
+}
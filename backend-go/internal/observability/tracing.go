@@ -0,0 +1,73 @@
+package observability
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer covers handler -> mlclient -> HTTP call, so slow ML predictions
+// show up as a span instead of just a log line.
+var tracer = otel.Tracer("backend-go")
+
+// tracerProvider is shut down from main via ShutdownTracing, flushing any
+// spans still buffered when the process exits.
+var tracerProvider *sdktrace.TracerProvider
+
+// initTracing registers a real OTel SDK TracerProvider exporting to the
+// collector at OTEL_EXPORTER_OTLP_ENDPOINT (default: otel-collector:4317).
+// Without this, otel.Tracer returns a no-op and every StartSpan call is
+// silently discarded.
+func initTracing() error {
+	exporter, err := otlptracegrpc.New(context.Background(),
+		otlptracegrpc.WithEndpoint(envOr("OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317")),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceNameKey.String("backend-go"),
+	))
+	if err != nil {
+		return err
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	tracer = tracerProvider.Tracer("backend-go")
+
+	return nil
+}
+
+// ShutdownTracing flushes buffered spans and stops the exporter. Call it
+// once from main on shutdown, after observability.Init.
+func ShutdownTracing(ctx context.Context) error {
+	if tracerProvider == nil {
+		return nil
+	}
+	return tracerProvider.Shutdown(ctx)
+}
+
+// StartSpan starts a span named name as a child of whatever span is
+// already on ctx (a no-op parent if none was set up, e.g. in tests).
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
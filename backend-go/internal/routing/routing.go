@@ -0,0 +1,140 @@
+// Package routing makes a browser session's access URL actually routable.
+// It registers a per-session Traefik router+service by writing to
+// Traefik's file provider directory as soon as a container starts, and
+// removes it again when the session ends — so
+// https://{sessionID}.disposable-services.duckdns.org is a real, ACME-able
+// route, not just a string the containers package happens to format.
+package routing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// domainSuffix is the shared domain every session gets a subdomain under.
+// It must be a real, publicly resolvable domain, since Let's Encrypt has
+// to be able to complete a challenge for *.domainSuffix — it is
+// overridable via ROUTING_DOMAIN_SUFFIX because disposable-services is
+// just the suffix this deployment happens to own on DuckDNS.
+var domainSuffix = envOr("ROUTING_DOMAIN_SUFFIX", "disposable-services.duckdns.org")
+
+// certResolver must match the ACME resolver name configured in Traefik's
+// static config (traefik.yml) — a DNS-01 resolver covering
+// *.domainSuffix, since each session mints a new subdomain on demand.
+const (
+	certResolver = "letsencrypt"
+	entryPoint   = "websecure"
+)
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// Router registers and tears down per-session Traefik routes via
+// Traefik's file provider: one dynamic-config file per session. Traefik
+// picks up a new router/service as soon as the file appears and drops it
+// the moment the file is removed, so no HTTP provider API calls or
+// restarts are needed.
+type Router struct {
+	configDir string
+}
+
+// NewRouter builds a Router that writes dynamic config files into
+// configDir, which must be one of Traefik's configured file-provider
+// watch directories.
+func NewRouter(configDir string) *Router {
+	return &Router{configDir: configDir}
+}
+
+type dynamicConfig struct {
+	HTTP httpConfig `yaml:"http"`
+}
+
+type httpConfig struct {
+	Routers  map[string]routerConfig  `yaml:"routers"`
+	Services map[string]serviceConfig `yaml:"services"`
+}
+
+type routerConfig struct {
+	Rule        string    `yaml:"rule"`
+	Service     string    `yaml:"service"`
+	EntryPoints []string  `yaml:"entryPoints"`
+	TLS         tlsConfig `yaml:"tls"`
+}
+
+type tlsConfig struct {
+	CertResolver string `yaml:"certResolver"`
+}
+
+type serviceConfig struct {
+	LoadBalancer loadBalancerConfig `yaml:"loadBalancer"`
+}
+
+type loadBalancerConfig struct {
+	Servers []serverConfig `yaml:"servers"`
+}
+
+type serverConfig struct {
+	URL string `yaml:"url"`
+}
+
+// Register writes a router+service for sessionID pointing at
+// containerHost:port (containerHost is the container's name or IP on the
+// network Traefik itself is attached to), making AccessURL(sessionID)
+// route to the container.
+func (r *Router) Register(sessionID, containerHost string, port int) error {
+	cfg := dynamicConfig{
+		HTTP: httpConfig{
+			Routers: map[string]routerConfig{
+				sessionID: {
+					Rule:        fmt.Sprintf("Host(`%s.%s`)", sessionID, domainSuffix),
+					Service:     sessionID,
+					EntryPoints: []string{entryPoint},
+					TLS:         tlsConfig{CertResolver: certResolver},
+				},
+			},
+			Services: map[string]serviceConfig{
+				sessionID: {
+					LoadBalancer: loadBalancerConfig{
+						Servers: []serverConfig{{URL: fmt.Sprintf("http://%s:%d", containerHost, port)}},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshaling traefik config for session %s: %w", sessionID, err)
+	}
+
+	if err := os.WriteFile(r.configPath(sessionID), data, 0o644); err != nil {
+		return fmt.Errorf("writing traefik config for session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Teardown removes sessionID's dynamic config file, dropping its router
+// and service as soon as Traefik's file watcher notices.
+func (r *Router) Teardown(sessionID string) error {
+	if err := os.Remove(r.configPath(sessionID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing traefik config for session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+func (r *Router) configPath(sessionID string) string {
+	return filepath.Join(r.configDir, fmt.Sprintf("session-%s.yml", sessionID))
+}
+
+// AccessURL returns the public HTTPS URL Register makes routable for
+// sessionID.
+func AccessURL(sessionID string) string {
+	return fmt.Sprintf("https://%s.%s", sessionID, domainSuffix)
+}
@@ -1,18 +1,44 @@
-package main 
+package main
 
 import (
+    "context"
+    "os"
+
     "github.com/gin-gonic/gin"
     "backend/internal/api"
+    "backend/internal/mlclient"
+    "backend/internal/observability"
     "log"
 )
 
 func main(){
+    auditLogPath := os.Getenv("AUDIT_LOG_PATH")
+    if auditLogPath == "" {
+        auditLogPath = "/var/log/thesis/audit.jsonl"
+    }
+
+    //structured logging, metrics, tracing, and the audit log sink must be
+    //up before anything can serve traffic
+    if err := observability.Init(auditLogPath); err != nil {
+        log.Fatalf("Unable to initialize observability: %v", err)
+    }
+    defer observability.Log.Sync()
+    defer observability.ShutdownTracing(context.Background())
+
+    //configure the ML predictor once up front, so the first concurrent
+    //requests don't race each other into doing it lazily
+    if err := mlclient.Configure(); err != nil {
+        log.Fatalf("Unable to configure ML client: %v", err)
+    }
+
     //initiale Gin router with default logger and recovery middleware
     router := gin.Default()
     // register application routes
     api.RegisterRoutes(router)
+    //expose Prometheus metrics for scraping
+    observability.RegisterMetricsRoute(router)
     //Start HTTP server on port 8080
     if err := router.Run(":8080"); err != nil {
         log.Fatalf("Unable to start server : %v", err)
     }
-}
\ No newline at end of file
+}